@@ -0,0 +1,115 @@
+// Copyright 2013 Julien Schmidt. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Handle is the function signature used to register request handlers.
+type Handle func(http.ResponseWriter, *http.Request)
+
+// Middleware wraps a Handle to produce another Handle, typically to add
+// cross-cutting behaviour (auth, logging, ...) around it.
+type Middleware func(Handle) Handle
+
+// Router is a minimal, thread-safe request multiplexer keyed by method and
+// cleaned path. It exists to give Group something to mount routes onto.
+type Router struct {
+	mu       sync.RWMutex
+	handlers map[string]map[string]Handle
+}
+
+// New returns an empty Router.
+func New() *Router {
+	return &Router{handlers: make(map[string]map[string]Handle)}
+}
+
+// Handle registers handle for method and path. path is cleaned with
+// CleanPathB before being stored.
+func (r *Router) Handle(method, path string, handle Handle) {
+	clean := string(CleanPathB([]byte(path)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byPath := r.handlers[method]
+	if byPath == nil {
+		byPath = make(map[string]Handle)
+		r.handlers[method] = byPath
+	}
+	byPath[clean] = handle
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	handle, ok := r.handlers[req.Method][string(CleanPathB([]byte(req.URL.Path)))]
+	r.mu.RUnlock()
+
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	handle(w, req)
+}
+
+// Group lets handlers be registered under a shared path prefix and a shared
+// middleware chain, then mounted onto a Router.
+type Group struct {
+	router     *Router
+	prefix     []byte
+	middleware []Middleware
+}
+
+// Group returns a Group rooted at prefix, which is joined and cleaned once
+// up front so later registrations only pay for joining the route's own path.
+func (r *Router) Group(prefix string) *Group {
+	return &Group{router: r, prefix: CleanPathB([]byte(prefix))}
+}
+
+// Group returns a sub-group rooted at prefix, joined onto g's own prefix.
+// The new group inherits g's middleware chain.
+func (g *Group) Group(prefix string) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     JoinPathB(g.prefix, []byte(prefix)),
+		middleware: append([]Middleware(nil), g.middleware...),
+	}
+}
+
+// Use appends mw to the group's middleware chain. Middleware registered
+// before a route is added wraps that route; order matches registration
+// order, with the first registered middleware running outermost.
+func (g *Group) Use(mw ...Middleware) {
+	g.middleware = append(g.middleware, mw...)
+}
+
+// Handle registers handle for method and path under the group's prefix,
+// wrapped by the group's middleware chain, and mounts it on the parent
+// Router. path is joined onto the prefix with JoinPathB, which preserves
+// a trailing slash rather than stripping it, so it keeps matching the
+// trailing-slash-sensitive CleanPathB keying Router.Handle uses.
+func (g *Group) Handle(method, path string, handle Handle) {
+	full := JoinPathB(g.prefix, []byte(path))
+
+	for i := len(g.middleware) - 1; i >= 0; i-- {
+		handle = g.middleware[i](handle)
+	}
+	g.router.Handle(method, string(full), handle)
+}
+
+// GET is a shortcut for g.Handle(http.MethodGet, path, handle).
+func (g *Group) GET(path string, handle Handle) { g.Handle(http.MethodGet, path, handle) }
+
+// POST is a shortcut for g.Handle(http.MethodPost, path, handle).
+func (g *Group) POST(path string, handle Handle) { g.Handle(http.MethodPost, path, handle) }
+
+// PUT is a shortcut for g.Handle(http.MethodPut, path, handle).
+func (g *Group) PUT(path string, handle Handle) { g.Handle(http.MethodPut, path, handle) }
+
+// DELETE is a shortcut for g.Handle(http.MethodDelete, path, handle).
+func (g *Group) DELETE(path string, handle Handle) { g.Handle(http.MethodDelete, path, handle) }
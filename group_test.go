@@ -0,0 +1,80 @@
+// Copyright 2013 Julien Schmidt. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGroupMount(t *testing.T) {
+	r := New()
+	api := r.Group("/api")
+	api.GET("/users", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	api.GET("/groups/", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	v1 := api.Group("v1/")
+	v1.GET("/ping", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	cases := []struct {
+		path string
+		code int
+	}{
+		{"/api/users", http.StatusOK},
+		{"/api/v1/ping", http.StatusTeapot},
+		{"/api/missing", http.StatusNotFound},
+		{"/api/groups/", http.StatusAccepted},
+		{"/api/groups", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != c.code {
+			t.Errorf("GET %s = %d, want %d", c.path, rec.Code, c.code)
+		}
+	}
+}
+
+func TestGroupMiddlewareOrder(t *testing.T) {
+	r := New()
+	g := r.Group("/")
+
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next Handle) Handle {
+			return func(w http.ResponseWriter, req *http.Request) {
+				order = append(order, name)
+				next(w, req)
+			}
+		}
+	}
+	g.Use(mw("first"), mw("second"))
+	g.GET("/ping", func(w http.ResponseWriter, req *http.Request) {
+		order = append(order, "handler")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	want := []string{"first", "second", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
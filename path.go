@@ -0,0 +1,588 @@
+// Copyright 2013 Julien Schmidt. All rights reserved.
+// Based on the path package, Copyright 2009 The Go Authors.
+// Use of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+package httprouter
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrBadPattern indicates a pattern was malformed.
+var ErrBadPattern = errors.New("syntax error in pattern")
+
+// CleanPath is the URL version of path.Clean, it returns a canonical URL path
+// for p, eliminating . and .. elements.
+//
+// The following rules are applied iteratively until no further processing can
+// be done:
+//	1. Replace multiple slashes with a single slash.
+//	2. Eliminate each . path name element (the current directory).
+//	3. Eliminate each inner .. path name element (the parent directory)
+//	   along with the non-.. element that precedes it.
+//	4. Eliminate .. elements that begin a rooted path:
+//	   that is, replace "/.." by "/" at the beginning of a path.
+//
+// If the result of this process is an empty string, "/" is returned.
+func CleanPath(p string) string {
+	return string(CleanPathB([]byte(p)))
+}
+
+// CleanPathB is the URL version of path.Clean, it returns a canonical URL path
+// for p, eliminating . and .. elements.
+//
+// The following rules are applied iteratively until no further processing can
+// be done:
+//	1. Replace multiple slashes with a single slash.
+//	2. Eliminate each . path name element (the current directory).
+//	3. Eliminate each inner .. path name element (the parent directory)
+//	   along with the non-.. element that precedes it.
+//	4. Eliminate .. elements that begin a rooted path:
+//	   that is, replace "/.." by "/" at the beginning of a path.
+//
+// If the result of this process is an empty string, "/" is returned.
+//
+// CleanPathB is a thin wrapper around CleanPathAppend(nil, p); callers on a
+// hot path that clean the same path repeatedly should call CleanPathAppend
+// directly with a reused buffer to avoid the allocation this wrapper makes.
+func CleanPathB(p []byte) []byte {
+	return CleanPathAppend(nil, p)
+}
+
+// CleanPathAppend appends the cleaned form of src to dst and returns the
+// extended buffer, following append's growth semantics: if dst has enough
+// spare capacity, the whole call makes no allocation. It performs the same
+// in-place compaction as CleanPathB, tracking a read index r into src and
+// relying on dst's own length as the write cursor, so a ".." backtracks by
+// shrinking dst back to its last '/' rather than by re-scanning src.
+func CleanPathAppend(dst, src []byte) []byte {
+	start := len(dst)
+
+	if len(src) == 0 {
+		return append(dst, '/')
+	}
+
+	n := len(src)
+	r := 0
+	if src[0] == '/' {
+		r = 1
+	}
+	dst = append(dst, '/')
+
+	trailing := n > 1 && src[n-1] == '/'
+
+	for r < n {
+		switch {
+		case src[r] == '/':
+			// empty path element, trailing slash is added after the end
+			r++
+
+		case src[r] == '.' && r+1 == n:
+			trailing = true
+			r++
+
+		case src[r] == '.' && src[r+1] == '/':
+			// . element
+			r += 2
+
+		case src[r] == '.' && src[r+1] == '.' && (r+2 == n || src[r+2] == '/'):
+			// .. element: remove back to the last /, and drop that
+			// separator too so a following element re-adds exactly one.
+			r += 3
+
+			if len(dst) > start+1 {
+				dst = dst[:len(dst)-1]
+				for len(dst) > start+1 && dst[len(dst)-1] != '/' {
+					dst = dst[:len(dst)-1]
+				}
+				if len(dst) > start+1 {
+					dst = dst[:len(dst)-1]
+				}
+			}
+
+		default:
+			// real path element.
+			// add slash if needed
+			if len(dst) > start+1 {
+				dst = append(dst, '/')
+			}
+
+			// copy element
+			for ; r < n && src[r] != '/'; r++ {
+				dst = append(dst, src[r])
+			}
+		}
+	}
+
+	// re-append trailing slash
+	if trailing && len(dst) > start+1 {
+		dst = append(dst, '/')
+	}
+
+	return dst
+}
+
+// CleanPathTo writes the cleaned form of src to w one byte at a time, for
+// streaming into a bufio.Writer or directly into an http.ResponseWriter
+// redirect header. It shares CleanPathAppend's compaction core against dst,
+// since a ".." backtracks bytes already produced and so cannot be written
+// to w before the rest of src has been seen; dst is reset with dst[:0]
+// before use, so callers on a hot path can pass a reused buffer to avoid
+// the allocation CleanPathAppend would otherwise make. It returns the
+// number of bytes written before any error from w.
+func CleanPathTo(w io.ByteWriter, dst, src []byte) (int, error) {
+	cleaned := CleanPathAppend(dst[:0], src)
+
+	for i, c := range cleaned {
+		if err := w.WriteByte(c); err != nil {
+			return i, err
+		}
+	}
+	return len(cleaned), nil
+}
+
+// JoinPathB joins dir and file into a single cleaned path without paying for
+// a []byte<->string round trip. Its semantics and implementation follow Go's
+// internal wasip1 joinPath, used to resolve openat-style paths relative to a
+// preopened directory: each slash-delimited segment of dir then file is
+// appended onto a shared buffer, "." segments are dropped and each ".."
+// removes the element before it. When dir is absolute, ".." never rises
+// above "/". Otherwise a ".." is itself kept when it follows a kept "..",
+// finds nothing to remove, or removes the only remaining element, so
+// JoinPathB("a", "..") is "..", not ".".
+//
+// Apart from a bare "/" result, the result ends in a slash iff file itself
+// ended in "/". An empty result becomes ".".
+func JoinPathB(dir, file []byte) []byte {
+	buf := make([]byte, 0, len(dir)+len(file)+1)
+	if len(dir) > 0 && dir[0] == '/' {
+		buf = append(buf, '/')
+	}
+
+	buf, lookupParent := appendCleanPath(buf, dir, false)
+	buf, _ = appendCleanPath(buf, file, lookupParent)
+
+	// appendCleanPath never writes a "." entry, so buf is left empty only
+	// when a relative dir and file hold nothing but "." segments and slashes.
+	if len(buf) == 0 {
+		buf = append(buf, '.')
+	}
+	if buf[len(buf)-1] != '/' && len(file) > 0 && file[len(file)-1] == '/' {
+		buf = append(buf, '/')
+	}
+	return buf
+}
+
+// appendCleanPath appends the cleaned segments of path to buf, eliding "."
+// segments and resolving ".." against whatever buf already holds. lookupParent
+// reports whether an unresolved ".." has already been seen (e.g. because dir
+// was relative and ran out of segments to pop) and must therefore keep being
+// appended literally rather than popping further; the returned bool carries
+// that state forward to the next call, chaining dir's result into file's.
+func appendCleanPath(buf, path []byte, lookupParent bool) ([]byte, bool) {
+	i := 0
+	for i < len(path) {
+		for i < len(path) && path[i] == '/' {
+			i++
+		}
+
+		j := i
+		for j < len(path) && path[j] != '/' {
+			j++
+		}
+
+		s := path[i:j]
+		i = j
+
+		switch {
+		case len(s) == 0, len(s) == 1 && s[0] == '.':
+			continue
+
+		case len(s) == 2 && s[0] == '.' && s[1] == '.':
+			if !lookupParent {
+				k := len(buf)
+				for k > 0 && buf[k-1] != '/' {
+					k--
+				}
+				for k > 1 && buf[k-1] == '/' {
+					k--
+				}
+				buf = buf[:k]
+				if k == 0 {
+					lookupParent = true
+				} else {
+					continue
+				}
+			}
+
+		default:
+			lookupParent = false
+		}
+
+		if len(buf) > 0 && buf[len(buf)-1] != '/' {
+			buf = append(buf, '/')
+		}
+		buf = append(buf, s...)
+	}
+	return buf, lookupParent
+}
+
+// Split splits path immediately following the final slash, separating it
+// into a directory and file name component. If there is no slash in path,
+// Split returns an empty dir and file set to path. Like path.Split, the
+// returned values have the property that dir+file == path. Both returned
+// slices alias the backing array of path.
+func Split(path []byte) (dir, file []byte) {
+	i := lastSlash(path)
+	return path[:i+1], path[i+1:]
+}
+
+// Dir returns all but the last element of path, typically the path's
+// directory. After dropping the final element using Split, the path is
+// Cleaned and trailing slashes are removed. If the path is empty, Dir
+// returns ".". If the path consists entirely of slashes followed by no
+// other bytes, Dir returns a single slash.
+func Dir(path []byte) []byte {
+	dir, _ := Split(path)
+	return CleanPathStrictB(dir)
+}
+
+// Base returns the last element of path. Trailing slashes are removed
+// before extracting the last element. If the path is empty, Base returns
+// ".". If the path consists entirely of slashes, Base returns "/".
+func Base(path []byte) []byte {
+	if len(path) == 0 {
+		return []byte(".")
+	}
+	// Strip trailing slashes.
+	for len(path) > 0 && path[len(path)-1] == '/' {
+		path = path[:len(path)-1]
+	}
+	// Find element after last slash.
+	if i := lastSlash(path); i >= 0 {
+		path = path[i+1:]
+	}
+	// If empty now, it had only slashes.
+	if len(path) == 0 {
+		return []byte("/")
+	}
+	return path
+}
+
+// Ext returns the file name extension used by path. The extension is the
+// suffix beginning at the final dot in the final element of path; it is
+// empty if there is no dot.
+func Ext(path []byte) []byte {
+	for i := len(path) - 1; i >= 0 && path[i] != '/'; i-- {
+		if path[i] == '.' {
+			return path[i:]
+		}
+	}
+	return nil
+}
+
+// lastSlash returns the index of the last slash in path, or -1 if path
+// contains no slash.
+func lastSlash(path []byte) int {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return i
+		}
+	}
+	return -1
+}
+
+// CleanPathStrictB implements the same lexical simplification as the
+// standard library's path.Clean, unlike CleanPathB which always roots the
+// result and collapses everything under it: an empty path cleans to ".",
+// leading ".." segments are retained rather than swallowed, no implicit
+// root is added, and the trailing slash is stripped except for the root.
+// This is useful for routers that proxy to upstreams expecting a relative
+// path, or that embed httprouter inside a VFS/fs.FS layer. It runs its own
+// single forward pass, tracking a read index r and a write index w into an
+// output buffer as long as path; it does not share CleanPathAppend's
+// compaction code, and it always allocates that output buffer.
+func CleanPathStrictB(path []byte) []byte {
+	if len(path) == 0 {
+		return []byte(".")
+	}
+
+	rooted := path[0] == '/'
+	n := len(path)
+
+	// Invariants:
+	//	reading from path; r is index of next byte to process.
+	//	writing to out; w is index of next byte to write.
+	//	dotdot is index in out where .. must stop, either because
+	//		it is the leading slash or it is a leading ../../.. prefix.
+	out := make([]byte, n)
+	r, w, dotdot := 0, 0, 0
+	if rooted {
+		out[0] = '/'
+		r, w, dotdot = 1, 1, 1
+	}
+
+	for r < n {
+		switch {
+		case path[r] == '/':
+			// empty path element
+			r++
+
+		case path[r] == '.' && (r+1 == n || path[r+1] == '/'):
+			// . element
+			r++
+
+		case path[r] == '.' && path[r+1] == '.' && (r+2 == n || path[r+2] == '/'):
+			// .. element: remove to last / unless at dotdot
+			r += 2
+			switch {
+			case w > dotdot:
+				// can backtrack
+				w--
+				for w > dotdot && out[w] != '/' {
+					w--
+				}
+			case !rooted:
+				// cannot backtrack, but not rooted, so append .. element.
+				if w > 0 {
+					out[w] = '/'
+					w++
+				}
+				out[w] = '.'
+				out[w+1] = '.'
+				w += 2
+				dotdot = w
+			}
+
+		default:
+			// real path element.
+			// add slash if needed
+			if rooted && w != 1 || !rooted && w != 0 {
+				out[w] = '/'
+				w++
+			}
+			// copy element
+			for ; r < n && path[r] != '/'; r++ {
+				out[w] = path[r]
+				w++
+			}
+		}
+	}
+
+	// Turn empty string into "."
+	if w == 0 {
+		return []byte(".")
+	}
+
+	return out[:w]
+}
+
+// MatchB reports whether name matches the shell pattern, implementing the
+// same semantics as path.Match but operating on byte slices to avoid
+// []byte<->string conversions on the hot path.
+//
+// The pattern syntax is:
+//
+//	pattern:
+//		{ term }
+//	term:
+//		'*'         matches any sequence of non-/ characters
+//		'?'         matches any single non-/ character
+//		'[' [ '^' ] { character-range } ']'
+//		            character class (must be non-empty)
+//		c           matches character c (c != '*', '?', '\\', '[')
+//		'\\' c      matches character c
+//
+//	character-range:
+//		c           matches character c (c != '\\', '-', ']')
+//		'\\' c      matches character c
+//		lo '-' hi   matches character c for lo <= c <= hi
+//
+// MatchB requires pattern to match all of name, not just a substring.
+// The only possible returned error is ErrBadPattern, when pattern is
+// malformed.
+//
+// Unlike path.Match, which backtracks recursively, MatchB performs a single
+// forward scan over pattern and name, remembering the most recent '*' and
+// the name index it was tried against (starIdx/matchIdx) so that a
+// mismatch can restart the match one byte further along, giving an
+// O(len(pattern)*len(name)) worst case.
+func MatchB(pattern, name []byte) (matched bool, err error) {
+	pIdx, nIdx := 0, 0
+	starIdx, matchIdx := -1, -1
+
+	for nIdx < len(name) {
+		if pIdx < len(pattern) {
+			switch c := pattern[pIdx]; c {
+			case '?':
+				if name[nIdx] != '/' {
+					pIdx++
+					nIdx++
+					continue
+				}
+
+			case '*':
+				starIdx = pIdx
+				matchIdx = nIdx
+				pIdx++
+				continue
+
+			case '[':
+				ok, next, cerr := matchClass(pattern, pIdx, name[nIdx])
+				if cerr != nil {
+					return false, cerr
+				}
+				if ok {
+					pIdx = next
+					nIdx++
+					continue
+				}
+				pIdx = next
+
+			case '\\':
+				if pIdx+1 >= len(pattern) {
+					return false, ErrBadPattern
+				}
+				if pattern[pIdx+1] == name[nIdx] {
+					pIdx += 2
+					nIdx++
+					continue
+				}
+				pIdx += 2
+
+			default:
+				if c == name[nIdx] {
+					pIdx++
+					nIdx++
+					continue
+				}
+			}
+		}
+
+		// Mismatch: backtrack to the last '*', if any, and retry matching
+		// one byte further into name. '*' never matches across a '/'.
+		if starIdx != -1 && name[matchIdx] != '/' {
+			matchIdx++
+			pIdx = starIdx + 1
+			nIdx = matchIdx
+			continue
+		}
+		// This mismatch can't be backtracked away, so the match is false
+		// regardless of what the rest of pattern says - but the rest of
+		// pattern may still be malformed, and path.Match reports that
+		// over a plain non-match, so validate it before returning.
+		if err := validatePattern(pattern, pIdx); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	// Only a run of trailing '*' terms can match the empty remainder; any
+	// other leftover term means name ran out too soon. Still validate the
+	// remaining pattern's syntax so a malformed escape or class reports
+	// ErrBadPattern rather than a plain non-match.
+	for pIdx < len(pattern) && pattern[pIdx] == '*' {
+		pIdx++
+	}
+	if pIdx < len(pattern) {
+		if err := validatePattern(pattern, pIdx); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// validatePattern reports whether pattern[start:] is syntactically
+// well-formed - every '\\' escapes a following byte and every '[' opens a
+// class that is closed by a matching ']' - without regard to whether it
+// would actually match anything. It lets MatchB surface ErrBadPattern for a
+// malformed tail even after an earlier, unrelated mismatch has already
+// decided the match is false.
+func validatePattern(pattern []byte, start int) error {
+	i := start
+	for i < len(pattern) {
+		switch pattern[i] {
+		case '\\':
+			i++
+			if i >= len(pattern) {
+				return ErrBadPattern
+			}
+			i++
+		case '[':
+			if _, next, err := matchClass(pattern, i, 0); err != nil {
+				return err
+			} else {
+				i = next
+			}
+		default:
+			i++
+		}
+	}
+	return nil
+}
+
+// matchClass parses the character class starting at pattern[start] (which
+// must be '[') and reports whether c is a member of it. next is the index
+// immediately following the closing ']'.
+func matchClass(pattern []byte, start int, c byte) (matched bool, next int, err error) {
+	i := start + 1
+	negated := false
+	if i < len(pattern) && pattern[i] == '^' {
+		negated = true
+		i++
+	}
+
+	first := true
+	for {
+		if i >= len(pattern) {
+			return false, i, ErrBadPattern
+		}
+		if pattern[i] == ']' && !first {
+			i++
+			break
+		}
+		first = false
+
+		lo := pattern[i]
+		if lo == '-' || lo == ']' {
+			return false, i, ErrBadPattern
+		}
+		if lo == '\\' {
+			i++
+			if i >= len(pattern) {
+				return false, i, ErrBadPattern
+			}
+			lo = pattern[i]
+		}
+		i++
+
+		hi := lo
+		if i < len(pattern) && pattern[i] == '-' {
+			i++
+			if i >= len(pattern) || pattern[i] == '-' || pattern[i] == ']' {
+				return false, i, ErrBadPattern
+			}
+			hi = pattern[i]
+			if hi == '\\' {
+				i++
+				if i >= len(pattern) {
+					return false, i, ErrBadPattern
+				}
+				hi = pattern[i]
+			}
+			i++
+		}
+
+		if lo <= c && c <= hi {
+			matched = true
+		}
+	}
+
+	if negated {
+		matched = !matched
+	}
+	return matched, i, nil
+}
@@ -7,6 +7,7 @@ package httprouter
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 )
 
@@ -145,3 +146,344 @@ func BenchmarkPathCleanLong(b *testing.B) {
 		}
 	}
 }
+
+// stdlibCleanTests mirrors the cleantests table from the standard library's
+// path package, which CleanPathStrictB follows (no implicit root, leading
+// ".." preserved), unlike cleanTests above which exercises CleanPathB.
+var stdlibCleanTests = []cleanPathTest{
+	// Already clean
+	{[]byte(""), []byte(".")},
+	{[]byte("abc"), []byte("abc")},
+	{[]byte("abc/def"), []byte("abc/def")},
+	{[]byte("a/b/c"), []byte("a/b/c")},
+	{[]byte("."), []byte(".")},
+	{[]byte(".."), []byte("..")},
+	{[]byte("../.."), []byte("../..")},
+	{[]byte("../../abc"), []byte("../../abc")},
+	{[]byte("/abc"), []byte("/abc")},
+	{[]byte("/"), []byte("/")},
+
+	// Remove trailing slash
+	{[]byte("abc/"), []byte("abc")},
+	{[]byte("abc/def/"), []byte("abc/def")},
+	{[]byte("a/b/c/"), []byte("a/b/c")},
+	{[]byte("./"), []byte(".")},
+	{[]byte("../"), []byte("..")},
+	{[]byte("../../"), []byte("../..")},
+	{[]byte("/abc/"), []byte("/abc")},
+
+	// Remove doubled slash
+	{[]byte("abc//def//ghi"), []byte("abc/def/ghi")},
+	{[]byte("//abc"), []byte("/abc")},
+	{[]byte("///abc"), []byte("/abc")},
+	{[]byte("//abc//"), []byte("/abc")},
+	{[]byte("abc//"), []byte("abc")},
+
+	// Remove . elements
+	{[]byte("abc/./def"), []byte("abc/def")},
+	{[]byte("/./abc/def"), []byte("/abc/def")},
+	{[]byte("abc/."), []byte("abc")},
+
+	// Remove .. elements
+	{[]byte("abc/def/ghi/../jkl"), []byte("abc/def/jkl")},
+	{[]byte("abc/def/../ghi/../jkl"), []byte("abc/jkl")},
+	{[]byte("abc/def/.."), []byte("abc")},
+	{[]byte("abc/def/../.."), []byte(".")},
+	{[]byte("/abc/def/../.."), []byte("/")},
+	{[]byte("abc/def/../../.."), []byte("..")},
+	{[]byte("/abc/def/../../.."), []byte("/")},
+	{[]byte("abc/def/../../../ghi/jkl/../../../mno"), []byte("../../mno")},
+	{[]byte("/../abc"), []byte("/abc")},
+	{[]byte("a/../b"), []byte("b")},
+
+	// Combinations
+	{[]byte("abc/./../def"), []byte("def")},
+	{[]byte("abc//./../def"), []byte("def")},
+	{[]byte("abc/../../././../def"), []byte("../../def")},
+}
+
+func TestCleanPathStrictB(t *testing.T) {
+	for _, test := range stdlibCleanTests {
+		if s := CleanPathStrictB(test.path); !bytes.Equal(s, test.result) {
+			t.Errorf("CleanPathStrictB(%q) = %q, want %q", test.path, s, test.result)
+		}
+		if s := CleanPathStrictB(test.result); !bytes.Equal(s, test.result) {
+			t.Errorf("CleanPathStrictB(%q) = %q, want %q", test.result, s, test.result)
+		}
+	}
+}
+
+func TestCleanPathAppendZeroAlloc(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping malloc count in short mode")
+	}
+	dst := make([]byte, 0, 1235)
+	for _, test := range cleanTests {
+		allocs := testing.AllocsPerRun(100, func() {
+			dst = CleanPathAppend(dst[:0], test.path)
+		})
+		if allocs > 0 {
+			t.Errorf("CleanPathAppend(dst[:0], %q): %v allocs, want zero", test.path, allocs)
+		}
+	}
+}
+
+func BenchmarkPathCleanAppendLong(b *testing.B) {
+	cleanTests := genLongPaths()
+	dst := make([]byte, 0, 1235)
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for _, test := range cleanTests {
+			dst = CleanPathAppend(dst[:0], test.path)
+		}
+	}
+}
+
+// errAtByteWriter is an io.ByteWriter whose first n calls to WriteByte
+// succeed and whose later calls fail with err, for exercising CleanPathTo's
+// error-propagation path.
+type errAtByteWriter struct {
+	n   int
+	err error
+}
+
+func (w *errAtByteWriter) WriteByte(c byte) error {
+	if w.n == 0 {
+		return w.err
+	}
+	w.n--
+	return nil
+}
+
+func TestCleanPathTo(t *testing.T) {
+	for _, test := range cleanTests {
+		var buf bytes.Buffer
+		n, err := CleanPathTo(&buf, nil, test.path)
+		if err != nil {
+			t.Errorf("CleanPathTo(%q) error = %v, want nil", test.path, err)
+		}
+		if n != buf.Len() || !bytes.Equal(buf.Bytes(), test.result) {
+			t.Errorf("CleanPathTo(%q) wrote %q, want %q", test.path, buf.Bytes(), test.result)
+		}
+	}
+
+	wantErr := errors.New("write failed")
+	w := &errAtByteWriter{n: 2, err: wantErr}
+	n, err := CleanPathTo(w, nil, []byte("/abc/def"))
+	if err != wantErr {
+		t.Errorf("CleanPathTo error = %v, want %v", err, wantErr)
+	}
+	if n != 2 {
+		t.Errorf("CleanPathTo wrote %d bytes before error, want 2", n)
+	}
+}
+
+func TestCleanPathToZeroAlloc(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping malloc count in short mode")
+	}
+	var discard bytes.Buffer
+	dst := make([]byte, 0, 1235)
+	for _, test := range cleanTests {
+		allocs := testing.AllocsPerRun(100, func() {
+			discard.Reset()
+			CleanPathTo(&discard, dst, test.path)
+		})
+		if allocs > 0 {
+			t.Errorf("CleanPathTo(dst, %q): %v allocs, want zero", test.path, allocs)
+		}
+	}
+}
+
+func BenchmarkPathCleanToLong(b *testing.B) {
+	cleanTests := genLongPaths()
+	var discard bytes.Buffer
+	dst := make([]byte, 0, 1235)
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		for _, test := range cleanTests {
+			discard.Reset()
+			CleanPathTo(&discard, dst, test.path)
+		}
+	}
+}
+
+type splitTest struct {
+	path, dir, file string
+}
+
+var splitTests = []splitTest{
+	{"a/b", "a/", "b"},
+	{"a/b/", "a/b/", ""},
+	{"a/", "a/", ""},
+	{"a", "", "a"},
+	{"/", "/", ""},
+}
+
+func TestSplit(t *testing.T) {
+	for _, test := range splitTests {
+		dir, file := Split([]byte(test.path))
+		if string(dir) != test.dir || string(file) != test.file {
+			t.Errorf("Split(%q) = %q, %q, want %q, %q", test.path, dir, file, test.dir, test.file)
+		}
+	}
+}
+
+type dirBaseTest struct {
+	path, dir, base string
+}
+
+var dirBaseTests = []dirBaseTest{
+	{"", ".", "."},
+	{".", ".", "."},
+	{"/.", "/", "."},
+	{"/", "/", "/"},
+	{"////", "/", "/"},
+	{"/foo", "/", "foo"},
+	{"x", ".", "x"},
+	{"abc/def", "abc", "def"},
+	{"a/b/.x", "a/b", ".x"},
+	{"a/b/c.", "a/b", "c."},
+	{"a/b/c.x", "a/b", "c.x"},
+	{"/a/b/c", "/a/b", "c"},
+	{"/a/b/c/", "/a/b/c", "c"},
+}
+
+func TestDir(t *testing.T) {
+	for _, test := range dirBaseTests {
+		if s := Dir([]byte(test.path)); string(s) != test.dir {
+			t.Errorf("Dir(%q) = %q, want %q", test.path, s, test.dir)
+		}
+	}
+}
+
+func TestBase(t *testing.T) {
+	for _, test := range dirBaseTests {
+		if s := Base([]byte(test.path)); string(s) != test.base {
+			t.Errorf("Base(%q) = %q, want %q", test.path, s, test.base)
+		}
+	}
+}
+
+type extTest struct {
+	path, ext string
+}
+
+var extTests = []extTest{
+	{"path.go", ".go"},
+	{"path", ""},
+	{"a.dir/b", ""},
+	{"a.dir/b.go", ".go"},
+	{"a.dir/", ""},
+}
+
+func TestExt(t *testing.T) {
+	for _, test := range extTests {
+		if s := Ext([]byte(test.path)); string(s) != test.ext {
+			t.Errorf("Ext(%q) = %q, want %q", test.path, s, test.ext)
+		}
+	}
+}
+
+type matchTest struct {
+	pattern, name string
+	match         bool
+	err           error
+}
+
+var matchTests = []matchTest{
+	{"abc", "abc", true, nil},
+	{"*", "abc", true, nil},
+	{"*c", "abc", true, nil},
+	{"a*", "a", true, nil},
+	{"a*", "abc", true, nil},
+	{"a*/b", "a*/b", true, nil},
+	{"a*b*c*d*e*/f", "axbxcxdxe/f", true, nil},
+	{"a*b?c*x", "abxbbxdbxebxczzx", true, nil},
+	{"a*b?c*x", "abxbbxdbxebxczzy", false, nil},
+	{"ab[c]", "abc", true, nil},
+	{"ab[b-d]", "abc", true, nil},
+	{"ab[e-g]", "abc", false, nil},
+	{"ab[^c]", "abc", false, nil},
+	{"ab[^b-d]", "abc", false, nil},
+	{"ab[^e-g]", "abc", true, nil},
+	{"a\\*b", "a*b", true, nil},
+	{"a?b", "a/b", false, nil},
+	{"a*b", "a/b", false, nil},
+	{"*", "", true, nil},
+	{"", "", true, nil},
+	{"", "x", false, nil},
+	{"[", "a", false, ErrBadPattern},
+	{"a\\", "a", false, ErrBadPattern},
+	{"a[", "x", false, ErrBadPattern},
+	{"a[", "a", false, ErrBadPattern},
+}
+
+type joinPathTest struct {
+	dir, file, result string
+}
+
+// joinPathTests ports the joinPathTests table from Go's wasip1 syscall
+// tests (src/syscall/fs_wasip1_test.go), which exercises JoinPathB's
+// namesake, syscall.JoinPath, against the same dir/file/result semantics.
+var joinPathTests = []joinPathTest{
+	{".", ".", "."},
+	{"./", "./", "./"},
+	{"././././", ".", "."},
+	{".", "./././", "./"},
+	{".", "a", "a"},
+	{".", "a/b", "a/b"},
+	{".", "..", ".."},
+	{".", "../", "../"},
+	{".", "../../", "../../"},
+	{".", "../..", "../.."},
+	{".", "../..//..///", "../../../"},
+	{"/", "/", "/"},
+	{"/", "a", "/a"},
+	{"/", "a/b", "/a/b"},
+	{"/a", "b", "/a/b"},
+	{"/", ".", "/"},
+	{"/", "..", "/"},
+	{"/", "../../", "/"},
+	{"/", "/../a/b/c", "/a/b/c"},
+	{"/", "/../a/b/c", "/a/b/c"},
+	{"/", "./hello/world", "/hello/world"},
+	{"/a", "../", "/"},
+	{"/a/b/c", "..", "/a/b"},
+	{"/a/b/c", "..///..///", "/a/"},
+	{"/a/b/c", "..///..///..", "/"},
+	{"/a/b/c", "..///..///..///..", "/"},
+	{"/a/b/c", "..///..///..///..///..", "/"},
+	{"/a/b/c/", "/d/e/f/", "/a/b/c/d/e/f/"},
+	{"a/b/c/", ".", "a/b/c"},
+	{"a/b/c/", "./d", "a/b/c/d"},
+	{"a/b/c/", "./d/", "a/b/c/d/"},
+	{"a/b/", "./c/d/", "a/b/c/d/"},
+	{"../", "..", "../.."},
+	{"a/b/c/d", "e/../..", "a/b/c"},
+	{"a/b/c/d", "./e/../..", "a/b/c"},
+	{"a/b/c/d", "./e/..//../../f/g//", "a/b/f/g/"},
+	{"../../../", "a/../../b/c", "../../b/c"},
+	{"/a/b/c", "/.././/hey!", "/a/b/hey!"},
+}
+
+func TestJoinPathB(t *testing.T) {
+	for _, test := range joinPathTests {
+		if s := JoinPathB([]byte(test.dir), []byte(test.file)); string(s) != test.result {
+			t.Errorf("JoinPathB(%q, %q) = %q, want %q", test.dir, test.file, s, test.result)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	for _, test := range matchTests {
+		ok, err := MatchB([]byte(test.pattern), []byte(test.name))
+		if ok != test.match || err != test.err {
+			t.Errorf("MatchB(%q, %q) = %v, %v, want %v, %v", test.pattern, test.name, ok, err, test.match, test.err)
+		}
+	}
+}